@@ -0,0 +1,171 @@
+// Package crawler provides the single HTTP client koradi-dl uses to talk to
+// koradi.org: a bounded worker pool, a per-host token-bucket rate limiter,
+// connection pooling/timeouts, a descriptive User-Agent, and robots.txt
+// enforcement, so the rest of the program never has to think about being
+// polite to the origin server.
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+	"golang.org/x/time/rate"
+)
+
+// userAgent identifies this tool to servers it crawls.
+const userAgent = "koradi-dl/1.0 (+https://github.com/harr1424/koradi-dl)"
+
+const (
+	requestsPerSecond = 2
+	burst             = 4
+
+	robotsCacheTTL = time.Hour
+)
+
+// Crawler bounds how many requests koradi-dl has in flight at once, paces
+// requests to each host, and refuses to fetch paths robots.txt disallows.
+// It is safe for concurrent use.
+type Crawler struct {
+	client *http.Client
+	sem    chan struct{}
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	robots   map[string]*robotsEntry
+}
+
+type robotsEntry struct {
+	group   *robotstxt.Group
+	fetched time.Time
+}
+
+// New returns a Crawler that allows at most maxConcurrency requests in
+// flight at once across all hosts. A maxConcurrency <= 0 defaults to
+// runtime.NumCPU()*2.
+func New(maxConcurrency int) *Crawler {
+	if maxConcurrency <= 0 {
+		maxConcurrency = runtime.NumCPU() * 2
+	}
+
+	return &Crawler{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				MaxIdleConns:        maxConcurrency * 2,
+				MaxIdleConnsPerHost: maxConcurrency,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		sem:      make(chan struct{}, maxConcurrency),
+		limiters: make(map[string]*rate.Limiter),
+		robots:   make(map[string]*robotsEntry),
+	}
+}
+
+// Get issues a GET request for rawURL through Do.
+func (c *Crawler) Get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build GET request for %s: %w", rawURL, err)
+	}
+	return c.Do(ctx, req)
+}
+
+// Do waits for a free worker slot and for the target host's rate limiter,
+// checks req's path against that host's robots.txt, and then performs the
+// request. Callers are responsible for closing the response body.
+func (c *Crawler) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	allowed, err := c.allowed(ctx, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("robots.txt disallows fetching %s", req.URL)
+	}
+
+	if err := c.limiterFor(req.URL.Host).Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-c.sem }()
+
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", userAgent)
+
+	return c.client.Do(req)
+}
+
+func (c *Crawler) limiterFor(host string) *rate.Limiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	l, ok := c.limiters[host]
+	if !ok {
+		l = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+		c.limiters[host] = l
+	}
+	return l
+}
+
+// allowed reports whether u.Path may be fetched according to host's
+// robots.txt, fetching and caching it on first use. A robots.txt that
+// cannot be fetched or parsed fails open rather than blocking the crawl.
+func (c *Crawler) allowed(ctx context.Context, u *url.URL) (bool, error) {
+	c.mu.Lock()
+	entry, cached := c.robots[u.Host]
+	c.mu.Unlock()
+
+	if !cached || time.Since(entry.fetched) > robotsCacheTTL {
+		group, err := c.fetchRobots(ctx, u)
+		if err != nil {
+			return true, nil
+		}
+		entry = &robotsEntry{group: group, fetched: time.Now()}
+		c.mu.Lock()
+		c.robots[u.Host] = entry
+		c.mu.Unlock()
+	}
+
+	if entry.group == nil {
+		return true, nil
+	}
+	return entry.group.Test(u.Path), nil
+}
+
+func (c *Crawler) fetchRobots(ctx context.Context, u *url.URL) (*robotstxt.Group, error) {
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	data, err := robotstxt.FromResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.FindGroup(userAgent), nil
+}
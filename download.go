@@ -0,0 +1,330 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	partSuffix = ".part"
+
+	// chunkedThreshold is the minimum Content-Length before a file is split
+	// into multiple Range-based chunks rather than downloaded as one stream.
+	chunkedThreshold = 20 * 1024 * 1024 // 20MB
+
+	chunkWorkers = 4
+
+	maxDownloadAttempts = 5
+	initialBackoff      = 500 * time.Millisecond
+)
+
+// retryableError wraps an error that is safe to retry (transient network
+// errors and 5xx responses), as opposed to errors that should abort the
+// download immediately (e.g. a 404).
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+func isRetryable(err error) bool {
+	var re *retryableError
+	return errors.As(err, &re)
+}
+
+// download fetches url into destPath, resuming from a `.part` file left over
+// from a previous attempt and, for large files, fetching multiple byte
+// ranges in parallel. The completed file is verified as a valid zip archive
+// before being renamed into place.
+func download(ctx context.Context, url string, destPath string) error {
+	partPath := destPath + partSuffix
+	start := time.Now()
+
+	var err error
+	backoff := initialBackoff
+	for attempt := 1; attempt <= maxDownloadAttempts; attempt++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err = attemptDownload(ctx, url, partPath)
+		if err == nil {
+			if ferr := finalizeDownload(partPath, destPath); ferr != nil {
+				slog.Error("download failed verification", "zip_url", url, "duration", time.Since(start), "error", ferr)
+				return ferr
+			}
+			bytes := int64(0)
+			if info, statErr := os.Stat(destPath); statErr == nil {
+				bytes = info.Size()
+			}
+			slog.Info("download complete", "zip_url", url, "bytes", bytes, "duration", time.Since(start))
+			return nil
+		}
+		if !isRetryable(err) {
+			slog.Error("download failed", "zip_url", url, "duration", time.Since(start), "error", err)
+			return err
+		}
+		if attempt == maxDownloadAttempts {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	wrapped := fmt.Errorf("download of %s failed after %d attempts: %w", url, maxDownloadAttempts, err)
+	slog.Error("download failed", "zip_url", url, "duration", time.Since(start), "error", wrapped)
+	return wrapped
+}
+
+func attemptDownload(ctx context.Context, url string, partPath string) error {
+	supportsRange, contentLength, err := probeRange(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	existingSize := int64(0)
+	if info, statErr := os.Stat(partPath); statErr == nil {
+		existingSize = info.Size()
+	}
+
+	if !supportsRange {
+		return downloadFull(ctx, url, partPath)
+	}
+
+	if contentLength > 0 && existingSize >= contentLength {
+		// Nothing left to fetch; the existing .part is already complete.
+		return nil
+	}
+
+	if contentLength >= chunkedThreshold {
+		return downloadChunked(ctx, url, partPath, contentLength)
+	}
+
+	return downloadRange(ctx, url, partPath, existingSize)
+}
+
+// probeRange issues a HEAD request to determine whether the server honors
+// Range requests and, if so, the total size of the resource.
+func probeRange(ctx context.Context, url string) (bool, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return false, 0, fmt.Errorf("unable to build HEAD request for %s: %w", url, err)
+	}
+
+	resp, err := httpCrawler.Do(ctx, req)
+	if err != nil {
+		return false, 0, &retryableError{fmt.Errorf("HEAD %s: %w", url, err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return false, 0, &retryableError{fmt.Errorf("HEAD %s: server returned %s", url, resp.Status)}
+	}
+	if resp.StatusCode >= 400 {
+		return false, 0, fmt.Errorf("HEAD %s: server returned %s", url, resp.Status)
+	}
+
+	supportsRange := resp.Header.Get("Accept-Ranges") == "bytes"
+	return supportsRange, resp.ContentLength, nil
+}
+
+// downloadFull fetches url in a single request with no resume support, for
+// servers that do not advertise Accept-Ranges.
+func downloadFull(ctx context.Context, url string, partPath string) error {
+	file, err := os.Create(partPath)
+	if err != nil {
+		return fmt.Errorf("unable to create %s: %w", partPath, err)
+	}
+	defer file.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("unable to build GET request for %s: %w", url, err)
+	}
+
+	resp, err := httpCrawler.Do(ctx, req)
+	if err != nil {
+		return &retryableError{fmt.Errorf("GET %s: %w", url, err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return &retryableError{fmt.Errorf("GET %s: server returned %s", url, resp.Status)}
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("GET %s: server returned %s", url, resp.Status)
+	}
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return &retryableError{fmt.Errorf("copying body from %s: %w", url, err)}
+	}
+
+	return nil
+}
+
+// downloadRange resumes a single-stream download starting at offset.
+func downloadRange(ctx context.Context, url string, partPath string, offset int64) error {
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %w", partPath, err)
+	}
+	defer file.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("unable to build GET request for %s: %w", url, err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := httpCrawler.Do(ctx, req)
+	if err != nil {
+		return &retryableError{fmt.Errorf("GET %s: %w", url, err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return &retryableError{fmt.Errorf("GET %s: server returned %s", url, resp.Status)}
+	}
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		return fmt.Errorf("GET %s: server returned %s", url, resp.Status)
+	}
+	if offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		// The server advertised Accept-Ranges on the HEAD but answered this
+		// ranged GET with a full 200 body instead of 206; writing it at
+		// offset would silently produce an oversized, corrupt .part file.
+		return fmt.Errorf("GET %s: requested range from byte %d but server returned %s instead of 206", url, offset, resp.Status)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("unable to seek %s: %w", partPath, err)
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return &retryableError{fmt.Errorf("copying body from %s: %w", url, err)}
+	}
+
+	return nil
+}
+
+// downloadChunked splits [0, totalSize) into chunkWorkers byte ranges and
+// fetches them concurrently, each worker writing into its own offset of a
+// preallocated file via WriteAt.
+func downloadChunked(ctx context.Context, url string, partPath string, totalSize int64) error {
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to create %s: %w", partPath, err)
+	}
+	defer file.Close()
+
+	if err := file.Truncate(totalSize); err != nil {
+		return fmt.Errorf("unable to preallocate %s: %w", partPath, err)
+	}
+
+	chunkSize := totalSize / chunkWorkers
+
+	var wg sync.WaitGroup
+	errs := make([]error, chunkWorkers)
+
+	for i := 0; i < chunkWorkers; i++ {
+		start := int64(i) * chunkSize
+		end := start + chunkSize - 1
+		if i == chunkWorkers-1 {
+			end = totalSize - 1
+		}
+
+		wg.Add(1)
+		go func(i int, start, end int64) {
+			defer wg.Done()
+			errs[i] = downloadChunk(ctx, url, file, start, end)
+		}(i, start, end)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func downloadChunk(ctx context.Context, url string, file *os.File, start, end int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("unable to build GET request for %s: %w", url, err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	resp, err := httpCrawler.Do(ctx, req)
+	if err != nil {
+		return &retryableError{fmt.Errorf("GET %s [%d-%d]: %w", url, start, end, err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return &retryableError{fmt.Errorf("GET %s [%d-%d]: server returned %s", url, start, end, resp.Status)}
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("GET %s [%d-%d]: expected 206, got %s", url, start, end, resp.Status)
+	}
+
+	buf := make([]byte, 0, end-start+1)
+	buf, err = readAll(resp.Body, buf)
+	if err != nil {
+		return &retryableError{fmt.Errorf("reading body from %s [%d-%d]: %w", url, start, end, err)}
+	}
+
+	if _, err := file.WriteAt(buf, start); err != nil {
+		return fmt.Errorf("writing %s [%d-%d]: %w", url, start, end, err)
+	}
+
+	return nil
+}
+
+func readAll(r io.Reader, buf []byte) ([]byte, error) {
+	for {
+		if len(buf) == cap(buf) {
+			buf = append(buf, 0)[:len(buf)]
+		}
+		n, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if err != nil {
+			if err == io.EOF {
+				return buf, nil
+			}
+			return buf, err
+		}
+	}
+}
+
+// finalizeDownload verifies that partPath is a well-formed zip archive and,
+// if so, renames it to destPath.
+func finalizeDownload(partPath string, destPath string) error {
+	if err := verifyZip(partPath); err != nil {
+		return fmt.Errorf("downloaded file %s failed verification: %w", partPath, err)
+	}
+
+	if err := os.Rename(partPath, destPath); err != nil {
+		return fmt.Errorf("unable to rename %s to %s: %w", partPath, destPath, err)
+	}
+
+	return nil
+}
+
+func verifyZip(path string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	return r.Close()
+}
@@ -1,10 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"net/http"
-	"os"
 	"strings"
 
 	"golang.org/x/net/html"
@@ -43,8 +42,8 @@ func removeDuplicates(input []string) []string {
 	return result
 }
 
-func scrape_authors(url string) ([]string, error) {
-	resp, err := http.Get(url)
+func scrape_authors(ctx context.Context, url string) ([]string, error) {
+	resp, err := httpCrawler.Get(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch URL %s: %w", url, err)
 	}
@@ -77,8 +76,8 @@ func scrape_authors(url string) ([]string, error) {
 	}
 }
 
-func scrape_zips(url string) ([]string, error) {
-	resp, err := http.Get(url)
+func scrape_zips(ctx context.Context, url string) ([]string, error) {
+	resp, err := httpCrawler.Get(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch URL %s: %w", url, err)
 	}
@@ -109,17 +108,3 @@ func scrape_zips(url string) ([]string, error) {
 		}
 	}
 }
-
-func download(url string, dest *os.File) error {
-	defer dest.Close()
-
-	resp, err := http.Get(url)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	_, err = io.Copy(dest, resp.Body)
-
-	return err
-}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"koradi_dl/lockfile"
+)
+
+// deriveTags derives filterable tags from a scraped zip URL: the language
+// code and, when present, the author slug that precedes the filename in the
+// URL path (e.g. https://koradi.org/en/downloads/some-author/talk.zip ->
+// ["en", "some-author"]).
+func deriveTags(lang string, zipURL string) []string {
+	tags := []string{lang}
+
+	u, err := url.Parse(zipURL)
+	if err != nil {
+		return tags
+	}
+
+	dir := path.Dir(u.Path)
+	author := path.Base(dir)
+	if author != "" && author != "." && author != "/" && author != lang {
+		tags = append(tags, author)
+	}
+
+	return tags
+}
+
+// filterByTag keeps only the zip URLs whose derived tags include tag.
+func filterByTag(zips []string, lang int, tag string) []string {
+	var filtered []string
+	for _, z := range zips {
+		for _, t := range deriveTags(get_lang(lang), z) {
+			if t == tag {
+				filtered = append(filtered, z)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// recordDownload hashes the freshly downloaded file and updates the
+// lockfile with its entry.
+func recordDownload(lock *lockfile.Lockfile, zipURL string, lang string, path_to_file string) error {
+	info, err := os.Stat(path_to_file)
+	if err != nil {
+		return fmt.Errorf("unable to stat %s: %w", path_to_file, err)
+	}
+
+	sum, err := lockfile.SHA256File(path_to_file)
+	if err != nil {
+		return fmt.Errorf("unable to hash %s: %w", path_to_file, err)
+	}
+
+	entry := lockfile.Entry{
+		URL:          zipURL,
+		Language:     lang,
+		Filename:     filepath.Base(path_to_file),
+		Size:         info.Size(),
+		SHA256:       sum,
+		LastModified: info.ModTime(),
+		Tags:         deriveTags(lang, zipURL),
+	}
+
+	return lock.Put(entry)
+}
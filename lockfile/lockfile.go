@@ -0,0 +1,245 @@
+// Package lockfile records reproducible state about every zip archive this
+// tool has downloaded, modeled on the lockfile approach used by tools like
+// grabit: a single JSON file that lets a user verify, on any machine, that
+// their local archive matches what was originally fetched.
+package lockfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"koradi_dl/storage"
+)
+
+// DefaultPath is the lockfile name written to the current working directory.
+const DefaultPath = "koradi.lock"
+
+// Entry describes a single successfully downloaded zip archive.
+type Entry struct {
+	URL          string    `json:"url"`
+	Language     string    `json:"language"`
+	Filename     string    `json:"filename"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	LastModified time.Time `json:"last_modified"`
+	Tags         []string  `json:"tags,omitempty"`
+}
+
+// HasTag reports whether the entry carries the given tag.
+func (e Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Lockfile is the in-memory, concurrency-safe representation of koradi.lock.
+type Lockfile struct {
+	mu      sync.Mutex
+	path    string
+	Entries []Entry `json:"entries"`
+}
+
+// Load reads the lockfile at path, returning an empty Lockfile if it does
+// not yet exist.
+func Load(path string) (*Lockfile, error) {
+	lf := &Lockfile{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lf, nil
+		}
+		return nil, fmt.Errorf("unable to read lockfile %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, lf); err != nil {
+		return nil, fmt.Errorf("unable to parse lockfile %s: %w", path, err)
+	}
+
+	return lf, nil
+}
+
+// Put inserts or replaces the entry for filename and atomically persists the
+// lockfile to disk.
+func (lf *Lockfile) Put(e Entry) error {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	replaced := false
+	for i, existing := range lf.Entries {
+		if existing.Filename == e.Filename {
+			lf.Entries[i] = e
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		lf.Entries = append(lf.Entries, e)
+	}
+
+	return lf.save()
+}
+
+// Find returns the entry recorded for filename, if any.
+func (lf *Lockfile) Find(filename string) (Entry, bool) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	for _, e := range lf.Entries {
+		if e.Filename == filename {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Filtered returns the entries matching tag, or all entries when tag is empty.
+func (lf *Lockfile) Filtered(tag string) []Entry {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	if tag == "" {
+		return append([]Entry(nil), lf.Entries...)
+	}
+
+	var matched []Entry
+	for _, e := range lf.Entries {
+		if e.HasTag(tag) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// save writes the lockfile to a temp file in the same directory and renames
+// it into place so a crash mid-write never leaves koradi.lock truncated.
+// Callers must hold lf.mu.
+func (lf *Lockfile) save() error {
+	data, err := json.MarshalIndent(lf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal lockfile: %w", err)
+	}
+
+	dir := filepath.Dir(lf.path)
+	tmp, err := os.CreateTemp(dir, ".koradi.lock.tmp-*")
+	if err != nil {
+		return fmt.Errorf("unable to create temp lockfile: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to write temp lockfile: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to close temp lockfile: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, lf.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to rename temp lockfile into place: %w", err)
+	}
+
+	return nil
+}
+
+// SHA256File computes the SHA-256 digest of the file at path.
+func SHA256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DriftStatus describes the outcome of verifying a single entry against disk.
+type DriftStatus string
+
+const (
+	StatusOK        DriftStatus = "ok"
+	StatusMissing   DriftStatus = "missing"
+	StatusDrifted   DriftStatus = "drifted"
+	StatusCorrupted DriftStatus = "corrupted"
+)
+
+// VerifyResult is the outcome of checking one lockfile entry against the
+// file it describes.
+type VerifyResult struct {
+	Entry  Entry
+	Status DriftStatus
+	Err    error
+}
+
+// Verify recomputes the hash of each entry's file as it exists on disk and
+// reports whether it matches what was recorded at download time. disk is
+// the same storage.Disk the entries were originally published to (local,
+// SFTP, FTP, or S3), so verification reflects the destination the user
+// actually cares about rather than a local scratch copy that may have
+// already been cleaned up.
+func Verify(disk storage.Disk, entries []Entry) []VerifyResult {
+	results := make([]VerifyResult, 0, len(entries))
+
+	for _, e := range entries {
+		path := filepath.Join(e.Language, e.Filename)
+
+		info, err := disk.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				results = append(results, VerifyResult{Entry: e, Status: StatusMissing})
+				continue
+			}
+			results = append(results, VerifyResult{Entry: e, Status: StatusCorrupted, Err: err})
+			continue
+		}
+
+		sum, err := sha256Disk(disk, path)
+		if err != nil {
+			results = append(results, VerifyResult{Entry: e, Status: StatusCorrupted, Err: err})
+			continue
+		}
+
+		if info.Size() != e.Size || sum != e.SHA256 {
+			results = append(results, VerifyResult{Entry: e, Status: StatusDrifted})
+			continue
+		}
+
+		results = append(results, VerifyResult{Entry: e, Status: StatusOK})
+	}
+
+	return results
+}
+
+// sha256Disk computes the SHA-256 digest of the file at path on disk.
+func sha256Disk(disk storage.Disk, path string) (string, error) {
+	f, err := disk.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+const (
+	logFilePath   = "koradi.log"
+	maxLogBackups = 3
+)
+
+// parseLogLevel maps the --log-level flag value to a slog.Level, defaulting
+// to info for anything unrecognized.
+func parseLogLevel(s string) slog.Level {
+	switch s {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// setupLogging installs the default slog.Logger: structured JSON records
+// always go to a rotating koradi.log, while the TTY handler gets either
+// JSON (when jsonStdout is set) or human-readable text. When send is
+// non-nil, a Bubble Tea program owns the terminal, so the TTY handler is
+// routed through send as logMsg events instead of writing to os.Stdout
+// directly, which would otherwise race with and corrupt the TUI's
+// progress-bar rendering. The returned func closes the log file and
+// should be deferred by the caller.
+func setupLogging(level slog.Level, jsonStdout bool, send func(tea.Msg)) (func() error, error) {
+	if err := rotateLog(logFilePath, maxLogBackups); err != nil {
+		return nil, fmt.Errorf("unable to rotate %s: %w", logFilePath, err)
+	}
+
+	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open %s: %w", logFilePath, err)
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	fileHandler := slog.NewJSONHandler(logFile, opts)
+
+	var ttyWriter io.Writer = os.Stdout
+	if send != nil {
+		ttyWriter = &teaLogWriter{send: send}
+	}
+
+	var stdoutHandler slog.Handler
+	if jsonStdout {
+		stdoutHandler = slog.NewJSONHandler(ttyWriter, opts)
+	} else {
+		stdoutHandler = slog.NewTextHandler(ttyWriter, opts)
+	}
+
+	slog.SetDefault(slog.New(newMultiHandler(fileHandler, stdoutHandler)))
+
+	return logFile.Close, nil
+}
+
+// teaLogWriter adapts an io.Writer to a Bubble Tea program's message
+// channel, so an slog.Handler can be pointed at it without ever touching
+// os.Stdout while the TUI is rendering. Each Write is one formatted log
+// line; it is forwarded as a logMsg instead of printed.
+type teaLogWriter struct {
+	send func(tea.Msg)
+}
+
+func (w *teaLogWriter) Write(p []byte) (int, error) {
+	line := strings.TrimRight(string(p), "\n")
+	if line != "" {
+		w.send(logMsg{Content: termenv.String(line)})
+	}
+	return len(p), nil
+}
+
+// rotateLog shifts any existing log and its numbered backups up by one
+// (path.2 -> path.3, path.1 -> path.2, path -> path.1), dropping backups
+// beyond maxBackups.
+func rotateLog(path string, maxBackups int) error {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	oldest := fmt.Sprintf("%s.%d", path, maxBackups)
+	if _, err := os.Stat(oldest); err == nil {
+		if err := os.Remove(oldest); err != nil {
+			return err
+		}
+	}
+
+	for i := maxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", path, i)
+		dst := fmt.Sprintf("%s.%d", path, i+1)
+		if _, err := os.Stat(src); err == nil {
+			if err := os.Rename(src, dst); err != nil {
+				return err
+			}
+		}
+	}
+
+	return os.Rename(path, path+".1")
+}
+
+// multiHandler fans log records out to several slog.Handlers, e.g. a JSON
+// file handler and a human-readable TTY handler.
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+func newMultiHandler(handlers ...slog.Handler) *multiHandler {
+	return &multiHandler{handlers: handlers}
+}
+
+func (m *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	for _, h := range m.handlers {
+		if !h.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, record.Clone()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return newMultiHandler(next...)
+}
+
+func (m *multiHandler) WithGroup(name string) slog.Handler {
+	next := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		next[i] = h.WithGroup(name)
+	}
+	return newMultiHandler(next...)
+}
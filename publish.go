@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"koradi_dl/storage"
+)
+
+// publishToDisk copies a completed local download into disk at remotePath.
+// Resumable, chunked downloads always land in a local scratch file first
+// (they need os.File.WriteAt), so this is what turns that local file into
+// whatever --dest actually points at. When disk is the default local
+// destination (the current working directory), the scratch file is already
+// in place and nothing further needs to happen.
+func publishToDisk(disk storage.Disk, localPath string, remotePath string) error {
+	if ld, ok := disk.(*storage.LocalDisk); ok {
+		if abs, err := filepath.Abs(ld.Dir); err == nil {
+			if cwd, err := os.Getwd(); err == nil && abs == cwd {
+				return nil
+			}
+		}
+	}
+
+	src, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("unable to open %s: %w", localPath, err)
+	}
+	defer src.Close()
+
+	dst, err := disk.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("unable to create %s on destination: %w", remotePath, err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("unable to upload %s: %w", remotePath, err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("unable to finalize upload of %s: %w", remotePath, err)
+	}
+
+	return os.Remove(localPath)
+}
@@ -0,0 +1,50 @@
+// Package storage abstracts where downloaded zip archives are written, so
+// koradi-dl can mirror Koradi's content to a local directory, a remote SFTP
+// or FTP server, or an S3-compatible bucket through the same run() code
+// path, rather than calling os.MkdirAll/os.Create/os.Stat directly.
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// Disk is the minimal set of filesystem-like operations run() needs against
+// a destination, regardless of whether it is local disk or a remote store.
+type Disk interface {
+	MkdirAll(path string) error
+	Create(path string) (io.WriteCloser, error)
+	Open(path string) (io.ReadCloser, error)
+	Stat(path string) (os.FileInfo, error)
+	WriteAt(path string, p []byte, off int64) error
+	Rename(oldpath, newpath string) error
+}
+
+// ErrUnsupported is returned by backends that cannot implement a given
+// operation, e.g. arbitrary-offset writes against an object store that only
+// accepts whole-object uploads.
+var ErrUnsupported = errors.New("operation not supported by this storage backend")
+
+// fileInfo is a minimal os.FileInfo implementation for backends (FTP, S3)
+// whose client libraries don't already return one.
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+func (f fileInfo) Name() string       { return f.name }
+func (f fileInfo) Size() int64        { return f.size }
+func (f fileInfo) ModTime() time.Time { return f.modTime }
+func (f fileInfo) IsDir() bool        { return f.isDir }
+func (f fileInfo) Sys() any           { return nil }
+
+func (f fileInfo) Mode() os.FileMode {
+	if f.isDir {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/textproto"
+	"os"
+	"path"
+	"time"
+
+	"github.com/jlaffaye/ftp"
+)
+
+// FTPDisk implements Disk against a remote directory over FTP, rooted at
+// Dir on the remote host. FTP has no concept of a random-access write, so
+// WriteAt is unsupported; only whole-file Create/upload is available.
+type FTPDisk struct {
+	conn *ftp.ServerConn
+	Dir  string
+}
+
+// NewFTPDisk dials host (host:port), logs in as user/password, and returns
+// a Disk rooted at dir on that host.
+func NewFTPDisk(host, user, password, dir string) (*FTPDisk, error) {
+	conn, err := ftp.Dial(host, ftp.DialWithTimeout(10*time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial %s: %w", host, err)
+	}
+
+	if err := conn.Login(user, password); err != nil {
+		conn.Quit()
+		return nil, fmt.Errorf("unable to log in to %s: %w", host, err)
+	}
+
+	if err := conn.MakeDir(dir); err != nil {
+		// Directory may already exist; FTP has no MkdirAll, so best-effort.
+		_ = err
+	}
+
+	return &FTPDisk{conn: conn, Dir: dir}, nil
+}
+
+func (d *FTPDisk) abs(p string) string {
+	return path.Join(d.Dir, p)
+}
+
+func (d *FTPDisk) MkdirAll(p string) error {
+	return d.conn.MakeDir(d.abs(p))
+}
+
+func (d *FTPDisk) Create(p string) (io.WriteCloser, error) {
+	return &ftpWriteCloser{conn: d.conn, path: d.abs(p)}, nil
+}
+
+func (d *FTPDisk) Open(p string) (io.ReadCloser, error) {
+	return d.conn.Retr(d.abs(p))
+}
+
+func (d *FTPDisk) Stat(p string) (os.FileInfo, error) {
+	size, err := d.conn.FileSize(d.abs(p))
+	if err != nil {
+		if tpErr, ok := err.(*textproto.Error); ok && tpErr.Code == ftp.StatusFileUnavailable {
+			return nil, fs.ErrNotExist
+		}
+		return nil, err
+	}
+	return fileInfo{name: path.Base(p), size: size}, nil
+}
+
+func (d *FTPDisk) WriteAt(p string, b []byte, off int64) error {
+	return ErrUnsupported
+}
+
+func (d *FTPDisk) Rename(oldpath, newpath string) error {
+	return d.conn.Rename(d.abs(oldpath), d.abs(newpath))
+}
+
+// ftpWriteCloser buffers a whole file in memory and uploads it on Close,
+// since the FTP STOR command expects a single streamed upload.
+type ftpWriteCloser struct {
+	conn *ftp.ServerConn
+	path string
+	buf  bytes.Buffer
+}
+
+func (w *ftpWriteCloser) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *ftpWriteCloser) Close() error {
+	return w.conn.Stor(w.path, &w.buf)
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalDisk implements Disk against the local filesystem, rooted at Dir.
+type LocalDisk struct {
+	Dir string
+}
+
+// NewLocalDisk returns a Disk rooted at dir, creating it if necessary.
+func NewLocalDisk(dir string) (*LocalDisk, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &LocalDisk{Dir: dir}, nil
+}
+
+func (d *LocalDisk) abs(path string) string {
+	return filepath.Join(d.Dir, path)
+}
+
+func (d *LocalDisk) MkdirAll(path string) error {
+	return os.MkdirAll(d.abs(path), 0755)
+}
+
+func (d *LocalDisk) Create(path string) (io.WriteCloser, error) {
+	return os.Create(d.abs(path))
+}
+
+func (d *LocalDisk) Open(path string) (io.ReadCloser, error) {
+	return os.Open(d.abs(path))
+}
+
+func (d *LocalDisk) Stat(path string) (os.FileInfo, error) {
+	return os.Stat(d.abs(path))
+}
+
+func (d *LocalDisk) WriteAt(path string, p []byte, off int64) error {
+	f, err := os.OpenFile(d.abs(path), os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteAt(p, off)
+	return err
+}
+
+func (d *LocalDisk) Rename(oldpath, newpath string) error {
+	return os.Rename(d.abs(oldpath), d.abs(newpath))
+}
@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// S3Disk implements Disk against an S3-compatible bucket, rooted at Prefix
+// within Bucket. Object stores have no real directories or random-offset
+// writes, so MkdirAll is a no-op and WriteAt is unsupported; whole objects
+// are uploaded via Create.
+type S3Disk struct {
+	client *s3.Client
+	Bucket string
+	Prefix string
+}
+
+// NewS3Disk loads AWS credentials/region from the environment (or shared
+// config files) and returns a Disk rooted at prefix within bucket.
+func NewS3Disk(bucket, prefix string) (*S3Disk, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS config: %w", err)
+	}
+
+	return &S3Disk{
+		client: s3.NewFromConfig(cfg),
+		Bucket: bucket,
+		Prefix: strings.Trim(prefix, "/"),
+	}, nil
+}
+
+func (d *S3Disk) key(p string) string {
+	if d.Prefix == "" {
+		return p
+	}
+	return path.Join(d.Prefix, p)
+}
+
+// MkdirAll is a no-op: S3 keys are not directories, they're just prefixes.
+func (d *S3Disk) MkdirAll(path string) error {
+	return nil
+}
+
+func (d *S3Disk) Create(p string) (io.WriteCloser, error) {
+	return &s3WriteCloser{client: d.client, bucket: d.Bucket, key: d.key(p)}, nil
+}
+
+func (d *S3Disk) Open(p string) (io.ReadCloser, error) {
+	out, err := d.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(d.key(p)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (d *S3Disk) Stat(p string) (os.FileInfo, error) {
+	out, err := d.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(d.key(p)),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fs.ErrNotExist
+		}
+		return nil, err
+	}
+
+	size := int64(0)
+	if out.ContentLength != nil {
+		size = *out.ContentLength
+	}
+	modTime := aws.ToTime(out.LastModified)
+
+	return fileInfo{name: path.Base(p), size: size, modTime: modTime}, nil
+}
+
+func (d *S3Disk) WriteAt(path string, p []byte, off int64) error {
+	return ErrUnsupported
+}
+
+func (d *S3Disk) Rename(oldpath, newpath string) error {
+	ctx := context.Background()
+	source := fmt.Sprintf("%s/%s", d.Bucket, d.key(oldpath))
+
+	if _, err := d.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(d.Bucket),
+		CopySource: aws.String(source),
+		Key:        aws.String(d.key(newpath)),
+	}); err != nil {
+		return fmt.Errorf("unable to copy %s to %s: %w", oldpath, newpath, err)
+	}
+
+	_, err := d.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(d.key(oldpath)),
+	})
+	return err
+}
+
+// isNotFound reports whether err is S3's way of saying an object doesn't
+// exist. HeadObject returns a generic 404 response rather than the typed
+// *types.NotFound GetObject/NotFoundException APIs use, so both are checked.
+func isNotFound(err error) bool {
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+
+	var respErr *smithyhttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() == 404 {
+		return true
+	}
+
+	return false
+}
+
+// s3WriteCloser buffers a whole object in memory and uploads it on Close,
+// matching S3's whole-object PutObject semantics.
+type s3WriteCloser struct {
+	client *s3.Client
+	bucket string
+	key    string
+	buf    bytes.Buffer
+}
+
+func (w *s3WriteCloser) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *s3WriteCloser) Close() error {
+	_, err := w.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(w.bucket),
+		Key:    aws.String(w.key),
+		Body:   bytes.NewReader(w.buf.Bytes()),
+	})
+	return err
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTPDisk implements Disk against a remote directory over SFTP, rooted at
+// Dir on the remote host.
+type SFTPDisk struct {
+	client *sftp.Client
+	conn   net.Conn
+	Dir    string
+}
+
+// NewSFTPDisk dials host (host:port) as user, authenticating with an
+// SSH agent or password, and returns a Disk rooted at dir on that host.
+// The host key is verified against known_hosts the same way the openssh
+// client does, so this refuses to talk to a host it cannot authenticate.
+func NewSFTPDisk(host, user, dir string) (*SFTPDisk, error) {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+
+	hostKeyCallback, err := knownHostsCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := sftpAuthMethods()
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User:            user,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	}
+
+	sshConn, err := ssh.Dial("tcp", host, config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial %s: %w", host, err)
+	}
+
+	client, err := sftp.NewClient(sshConn)
+	if err != nil {
+		sshConn.Close()
+		return nil, fmt.Errorf("unable to start sftp session with %s: %w", host, err)
+	}
+
+	if err := client.MkdirAll(dir); err != nil {
+		client.Close()
+		sshConn.Close()
+		return nil, fmt.Errorf("unable to create remote directory %s: %w", dir, err)
+	}
+
+	return &SFTPDisk{client: client, Dir: dir}, nil
+}
+
+func (d *SFTPDisk) abs(p string) string {
+	return path.Join(d.Dir, p)
+}
+
+func (d *SFTPDisk) MkdirAll(p string) error {
+	return d.client.MkdirAll(d.abs(p))
+}
+
+func (d *SFTPDisk) Create(p string) (io.WriteCloser, error) {
+	return d.client.Create(d.abs(p))
+}
+
+func (d *SFTPDisk) Open(p string) (io.ReadCloser, error) {
+	return d.client.Open(d.abs(p))
+}
+
+func (d *SFTPDisk) Stat(p string) (os.FileInfo, error) {
+	return d.client.Stat(d.abs(p))
+}
+
+func (d *SFTPDisk) WriteAt(p string, b []byte, off int64) error {
+	f, err := d.client.OpenFile(d.abs(p), os.O_CREATE|os.O_WRONLY)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteAt(b, off)
+	return err
+}
+
+func (d *SFTPDisk) Rename(oldpath, newpath string) error {
+	return d.client.Rename(d.abs(oldpath), d.abs(newpath))
+}
+
+// knownHostsCallback builds an ssh.HostKeyCallback backed by an OpenSSH
+// known_hosts file, defaulting to ~/.ssh/known_hosts or $KORADI_SFTP_KNOWN_HOSTS
+// when set. Unlike ssh.InsecureIgnoreHostKey, this rejects hosts whose key
+// isn't already recorded, which is the whole point of talking to someone
+// else's server over --dest sftp://.
+func knownHostsCallback() (ssh.HostKeyCallback, error) {
+	path := os.Getenv("KORADI_SFTP_KNOWN_HOSTS")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to locate known_hosts: %w", err)
+		}
+		path = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load known_hosts file %s: %w", path, err)
+	}
+	return callback, nil
+}
+
+// sftpAuthMethods prefers an SSH agent (the usual flow implied by the
+// --dest sftp://user@host/path syntax) and falls back to the
+// KORADI_SFTP_PASSWORD env var when no agent is reachable.
+func sftpAuthMethods() ([]ssh.AuthMethod, error) {
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		conn, err := net.Dial("unix", sock)
+		if err != nil {
+			return nil, fmt.Errorf("unable to connect to SSH agent at %s: %w", sock, err)
+		}
+		return []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(conn).Signers)}, nil
+	}
+
+	if password := os.Getenv("KORADI_SFTP_PASSWORD"); password != "" {
+		return []ssh.AuthMethod{ssh.Password(password)}, nil
+	}
+
+	return nil, fmt.Errorf("no SSH authentication available: set SSH_AUTH_SOCK (ssh-agent) or KORADI_SFTP_PASSWORD")
+}
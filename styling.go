@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -22,6 +23,7 @@ type model struct {
 	done         bool
 	mu           sync.Mutex
 	progressBars []progress.Model
+	cancel       context.CancelFunc
 }
 
 type logMsg LogMessage
@@ -36,6 +38,18 @@ func (m *model) Init() tea.Cmd {
 }
 
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			if m.cancel != nil {
+				m.cancel()
+			}
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -76,10 +90,16 @@ func (m *model) View() string {
 		sb.WriteString(fmt.Sprintf("Language %s: %s\n", m.languages[i], m.progressBars[i].View()))
 	}
 
+	if m.done {
+		sb.WriteString("\nDone. Press q to quit.\n")
+	} else {
+		sb.WriteString("\nPress q or ctrl+c to cancel and quit.\n")
+	}
+
 	return sb.String()
 }
 
-func newModel() *model {
+func newModel(cancel context.CancelFunc) *model {
 	languages := []string{"en", "es", "fr", "po", "it", "de"}
 	progressBars := make([]progress.Model, len(languages))
 	totalTasks := make([]int, len(languages))
@@ -95,5 +115,6 @@ func newModel() *model {
 		totalTasks:   totalTasks,
 		languages:    languages,
 		progressBars: progressBars,
+		cancel:       cancel,
 	}
 }
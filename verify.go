@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/muesli/termenv"
+
+	"koradi_dl/lockfile"
+	"koradi_dl/storage"
+)
+
+// runVerify recomputes hashes for files already recorded in koradi.lock and
+// reports any that are missing, drifted, or corrupted, without
+// re-downloading anything. It checks the files against disk (the same
+// --dest destination they were published to), not a local scratch copy,
+// since publishToDisk removes the local copy once it has been mirrored
+// to a non-default destination.
+func runVerify(tag string, disk storage.Disk) {
+	output := termenv.NewOutput(os.Stdout)
+
+	lock, err := lockfile.Load(lockfile.DefaultPath)
+	if err != nil {
+		msg := output.String(fmt.Sprintf("Unable to load %s: %v", lockfile.DefaultPath, err)).
+			Foreground(output.Color("1"))
+		fmt.Println(msg)
+		return
+	}
+
+	entries := lock.Filtered(tag)
+	if len(entries) == 0 {
+		fmt.Println("No lockfile entries to verify.")
+		return
+	}
+
+	results := lockfile.Verify(disk, entries)
+
+	okCount := 0
+	for _, r := range results {
+		switch r.Status {
+		case lockfile.StatusOK:
+			okCount++
+		case lockfile.StatusMissing:
+			msg := output.String(fmt.Sprintf("MISSING  %s (%s)", r.Entry.Filename, r.Entry.Language)).
+				Foreground(output.Color("1"))
+			fmt.Println(msg)
+		case lockfile.StatusDrifted:
+			msg := output.String(fmt.Sprintf("DRIFTED  %s (%s): on-disk hash no longer matches koradi.lock", r.Entry.Filename, r.Entry.Language)).
+				Foreground(output.Color("1"))
+			fmt.Println(msg)
+		case lockfile.StatusCorrupted:
+			msg := output.String(fmt.Sprintf("CORRUPT  %s (%s): %v", r.Entry.Filename, r.Entry.Language, r.Err)).
+				Foreground(output.Color("1"))
+			fmt.Println(msg)
+		}
+	}
+
+	fmt.Printf("\nVerified %d/%d files OK.\n", okCount, len(results))
+}